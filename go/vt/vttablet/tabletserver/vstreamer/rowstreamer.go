@@ -18,7 +18,13 @@ package vstreamer
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"vitess.io/vitess/go/mysql"
 	"vitess.io/vitess/go/sqltypes"
@@ -32,15 +38,234 @@ import (
 	querypb "vitess.io/vitess/go/vt/proto/query"
 )
 
+// DefaultParallelism preserves the original single-connection, ordered scan
+// behavior of rowStreamer.
+const DefaultParallelism = 1
+
+// DefaultTargetChunkDuration is how long rowStreamer aims to spend fetching
+// each packet's worth of rows. The row count per packet adapts toward this
+// target; *PacketSize still caps a packet's bytes.
+const DefaultTargetChunkDuration = 100 * time.Millisecond
+
+// sqlString renders s as a quoted SQL string literal, for building queries
+// against information_schema where a bind variable isn't available.
+func sqlString(s string) string {
+	return sqlparser.String(sqlparser.NewStrVal([]byte(s)))
+}
+
+// parseInt64 pulls an int64 out of a query result cell, e.g. a COUNT(*) or
+// a SHOW TABLE STATUS Rows column.
+func parseInt64(v sqltypes.Value) (int64, error) {
+	return strconv.ParseInt(v.ToString(), 10, 64)
+}
+
+// parseFloat64 pulls a float64 out of a query result cell, e.g. a custom
+// throttler metric query.
+func parseFloat64(v sqltypes.Value) (float64, error) {
+	return strconv.ParseFloat(v.ToString(), 64)
+}
+
 // RowStreamer exposes an externally usable interface to rowStreamer.
 type RowStreamer interface {
 	Stream() error
 	Cancel()
 }
 
+// RowsEstimateMethod identifies the strategy rowStreamer uses to derive the
+// total row estimate it reports alongside a copy.
+type RowsEstimateMethod string
+
+const (
+	// TableStatusRowsEstimate reads the `Rows` column out of
+	// `SHOW TABLE STATUS LIKE '<table>'`. This is cheap but, on InnoDB,
+	// only an approximation.
+	TableStatusRowsEstimate RowsEstimateMethod = "table_status"
+	// ExplainRowsEstimate runs `EXPLAIN` against the streaming query and
+	// uses the optimizer's `rows` estimate. It is slightly more
+	// expensive than TableStatusRowsEstimate but tracks the actual
+	// predicate being streamed (e.g. a resumed `pk > lastpk` scan).
+	ExplainRowsEstimate RowsEstimateMethod = "explain"
+	// CountRowsEstimate runs `SELECT COUNT(*)` under the same snapshot
+	// used for the copy. It is exact but can be slow on large tables.
+	CountRowsEstimate RowsEstimateMethod = "count"
+)
+
+// DefaultRowsEstimateMethod is used when the caller doesn't request a
+// specific estimation strategy.
+const DefaultRowsEstimateMethod = TableStatusRowsEstimate
+
+// Throttler is consulted by rowStreamer between packet sends so an operator
+// can regulate how fast a copy runs against live production traffic, the
+// same way online-schema-change tools throttle their copy workers.
+type Throttler interface {
+	// Throttle blocks until it is ok to send the next packet, or ctx is
+	// done, whichever comes first.
+	Throttle(ctx context.Context) error
+}
+
+type noopThrottler struct{}
+
+func (noopThrottler) Throttle(ctx context.Context) error { return nil }
+
+// ReplicationLagThrottler is a Throttler that polls a replica's
+// `SHOW SLAVE STATUS` and blocks sends while `Seconds_Behind_Master`
+// exceeds maxLagSeconds.
+type ReplicationLagThrottler struct {
+	cp            *mysql.ConnParams
+	maxLagSeconds int64
+	pollInterval  time.Duration
+}
+
+// NewReplicationLagThrottler returns a Throttler that polls the replica at
+// cp and throttles while it is lagging by more than maxLagSeconds.
+func NewReplicationLagThrottler(cp *mysql.ConnParams, maxLagSeconds int64, pollInterval time.Duration) *ReplicationLagThrottler {
+	return &ReplicationLagThrottler{cp: cp, maxLagSeconds: maxLagSeconds, pollInterval: pollInterval}
+}
+
+func (t *ReplicationLagThrottler) Throttle(ctx context.Context) error {
+	for {
+		lag, err := t.currentLag(ctx)
+		if err != nil {
+			return err
+		}
+		if lag <= t.maxLagSeconds {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(t.pollInterval):
+		}
+	}
+}
+
+func (t *ReplicationLagThrottler) currentLag(ctx context.Context) (int64, error) {
+	conn, err := mysql.Connect(ctx, t.cp)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	qr, err := conn.ExecuteFetch("show slave status", 1, true)
+	if err != nil {
+		return 0, err
+	}
+	if len(qr.Rows) != 1 {
+		return 0, fmt.Errorf("unexpected result for show slave status: %v", qr.Rows)
+	}
+	for i, fld := range qr.Fields {
+		if fld.Name == "Seconds_Behind_Master" {
+			return parseInt64(qr.Rows[0][i])
+		}
+	}
+	return 0, fmt.Errorf("show slave status did not return a Seconds_Behind_Master column")
+}
+
+// MetricQueryThrottler is a Throttler that runs a user-supplied SQL query
+// returning a single numeric metric and blocks sends while that metric
+// exceeds maxValue. This lets an operator throttle on anything they can
+// express as a query (custom lag proxies, queue depth, and so on).
+type MetricQueryThrottler struct {
+	cp           *mysql.ConnParams
+	query        string
+	maxValue     float64
+	pollInterval time.Duration
+}
+
+// NewMetricQueryThrottler returns a Throttler that runs query against cp and
+// throttles while the single numeric value it returns exceeds maxValue.
+func NewMetricQueryThrottler(cp *mysql.ConnParams, query string, maxValue float64, pollInterval time.Duration) *MetricQueryThrottler {
+	return &MetricQueryThrottler{cp: cp, query: query, maxValue: maxValue, pollInterval: pollInterval}
+}
+
+func (t *MetricQueryThrottler) Throttle(ctx context.Context) error {
+	for {
+		value, err := t.currentValue(ctx)
+		if err != nil {
+			return err
+		}
+		if value <= t.maxValue {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(t.pollInterval):
+		}
+	}
+}
+
+func (t *MetricQueryThrottler) currentValue(ctx context.Context) (float64, error) {
+	conn, err := mysql.Connect(ctx, t.cp)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	qr, err := conn.ExecuteFetch(t.query, 1, false)
+	if err != nil {
+		return 0, err
+	}
+	if len(qr.Rows) != 1 || len(qr.Rows[0]) != 1 {
+		return 0, fmt.Errorf("throttler metric query must return exactly one row and column, got: %v", qr.Rows)
+	}
+	return parseFloat64(qr.Rows[0][0])
+}
+
+// adaptiveChunker picks how many rows to batch into each packet, growing or
+// shrinking that count toward a target fetch duration instead of cutting
+// packets off purely at a fixed row count. *PacketSize remains a hard byte
+// ceiling alongside it (see shouldFlush) so a copy of wide BLOB/TEXT rows
+// can't grow a packet past any sane gRPC message-size limit just because
+// the duration target hasn't been reached yet.
+type adaptiveChunker struct {
+	targetDuration time.Duration
+	rowsPerPacket  int64
+	minRows        int64
+	maxRows        int64
+}
+
+func newAdaptiveChunker(targetDuration time.Duration) *adaptiveChunker {
+	if targetDuration <= 0 {
+		targetDuration = DefaultTargetChunkDuration
+	}
+	return &adaptiveChunker{
+		targetDuration: targetDuration,
+		rowsPerPacket:  100,
+		minRows:        10,
+		maxRows:        100000,
+	}
+}
+
+func (c *adaptiveChunker) shouldFlush(rowsInPacket, bytesInPacket int64) bool {
+	return rowsInPacket >= c.rowsPerPacket || bytesInPacket >= int64(*PacketSize)
+}
+
+// recordBatch adjusts rowsPerPacket based on how long the last batch took to
+// fetch relative to the target, growing or shrinking by at most 2x per
+// round so a single slow or fast batch can't swing the target wildly.
+func (c *adaptiveChunker) recordBatch(elapsed time.Duration) {
+	if elapsed <= 0 {
+		return
+	}
+	next := float64(c.rowsPerPacket) * float64(c.targetDuration) / float64(elapsed)
+	if max := float64(c.rowsPerPacket) * 2; next > max {
+		next = max
+	}
+	if min := float64(c.rowsPerPacket) * 0.5; next < min {
+		next = min
+	}
+	rows := int64(next)
+	if rows < c.minRows {
+		rows = c.minRows
+	}
+	if rows > c.maxRows {
+		rows = c.maxRows
+	}
+	c.rowsPerPacket = rows
+}
+
 // NewRowStreamer returns a RowStreamer
-func NewRowStreamer(ctx context.Context, cp *mysql.ConnParams, se *schema.Engine, query string, lastpk []sqltypes.Value, send func(*binlogdatapb.VStreamRowsResponse) error) RowStreamer {
-	return newRowStreamer(ctx, cp, se, query, lastpk, &localVSchema{vschema: &vindexes.VSchema{}}, send)
+func NewRowStreamer(ctx context.Context, cp *mysql.ConnParams, se *schema.Engine, query string, lastpk []sqltypes.Value, estimateRowsMethod RowsEstimateMethod, useConsistentSnapshot bool, parallelism int, throttler Throttler, send func(*binlogdatapb.VStreamRowsResponse) error) RowStreamer {
+	return newRowStreamer(ctx, cp, se, query, lastpk, &localVSchema{vschema: &vindexes.VSchema{}}, estimateRowsMethod, useConsistentSnapshot, parallelism, throttler, send)
 }
 
 type rowStreamer struct {
@@ -57,19 +282,69 @@ type rowStreamer struct {
 	plan      *Plan
 	pkColumns []int
 	sendQuery string
+
+	// pkDescending and pkNullable are parallel to pkColumns: they record,
+	// per PK column, whether it's declared DESC in the primary key and
+	// whether it's nullable. generatedColumns is parallel to
+	// plan.Table.Columns and marks which columns are generated.
+	pkDescending     []bool
+	pkNullable       []bool
+	generatedColumns []bool
+
+	estimateRowsMethod RowsEstimateMethod
+	rowsEstimate       int64
+
+	rowsCopied  int64
+	bytesCopied int64
+
+	// useConsistentSnapshot, when true, captures the streaming position
+	// from inside a REPEATABLE READ consistent-snapshot transaction on
+	// the streaming connection itself instead of taking a global
+	// LOCK TABLES ... READ on a side connection. This avoids stalling
+	// writers on the table for the duration of position capture, at the
+	// cost of requiring a GTID-consistent-snapshot capable server.
+	useConsistentSnapshot bool
+
+	// parallelism is the number of contiguous PK ranges the table is
+	// split into for copying, each streamed by its own connection. 1
+	// preserves the original single ordered scan.
+	parallelism int
+
+	// throttler is consulted between packet sends to pace the copy
+	// against live traffic.
+	throttler Throttler
+
+	// targetChunkDuration is how long each packet's row fetch should
+	// aim to take; rowsPerPacket in the adaptive chunker grows/shrinks
+	// toward it, with *PacketSize kept only as a byte ceiling.
+	targetChunkDuration time.Duration
 }
 
-func newRowStreamer(ctx context.Context, cp *mysql.ConnParams, se *schema.Engine, query string, lastpk []sqltypes.Value, vschema *localVSchema, send func(*binlogdatapb.VStreamRowsResponse) error) *rowStreamer {
+func newRowStreamer(ctx context.Context, cp *mysql.ConnParams, se *schema.Engine, query string, lastpk []sqltypes.Value, vschema *localVSchema, estimateRowsMethod RowsEstimateMethod, useConsistentSnapshot bool, parallelism int, throttler Throttler, send func(*binlogdatapb.VStreamRowsResponse) error) *rowStreamer {
 	ctx, cancel := context.WithCancel(ctx)
+	if estimateRowsMethod == "" {
+		estimateRowsMethod = DefaultRowsEstimateMethod
+	}
+	if parallelism <= 0 {
+		parallelism = DefaultParallelism
+	}
+	if throttler == nil {
+		throttler = noopThrottler{}
+	}
 	return &rowStreamer{
-		ctx:     ctx,
-		cancel:  cancel,
-		cp:      cp,
-		se:      se,
-		query:   query,
-		lastpk:  lastpk,
-		send:    send,
-		vschema: vschema,
+		ctx:                   ctx,
+		cancel:                cancel,
+		cp:                    cp,
+		se:                    se,
+		query:                 query,
+		lastpk:                lastpk,
+		send:                  send,
+		vschema:               vschema,
+		estimateRowsMethod:    estimateRowsMethod,
+		useConsistentSnapshot: useConsistentSnapshot,
+		parallelism:           parallelism,
+		throttler:             throttler,
+		targetChunkDuration:   DefaultTargetChunkDuration,
 	}
 }
 
@@ -88,6 +363,17 @@ func (rs *rowStreamer) Stream() error {
 		return err
 	}
 
+	if rs.parallelism > 1 {
+		if desc, uniform := rs.uniformPKDirection(); uniform && !desc {
+			return rs.streamParallel()
+		}
+		// planPKRanges samples range boundaries in ascending PK-value
+		// order, and a descending or mixed-direction PK can't be split
+		// into disjoint ranges on that same assumption without the
+		// bounds and the per-column resume direction disagreeing.
+		log.Warningf("table %s has a non-ascending or mixed-direction primary key; falling back to a single-range copy instead of the requested %d-way parallel copy", rs.plan.Table.Name, rs.parallelism)
+	}
+
 	conn, err := rs.mysqlConnect()
 	if err != nil {
 		return err
@@ -122,6 +408,9 @@ func (rs *rowStreamer) buildPlan() error {
 	if err != nil {
 		return err
 	}
+	if err := rs.loadPKColumnMeta(); err != nil {
+		return err
+	}
 	rs.sendQuery, err = rs.buildSelect()
 	if err != nil {
 		return err
@@ -129,6 +418,63 @@ func (rs *rowStreamer) buildPlan() error {
 	return err
 }
 
+// loadPKColumnMeta fills in, for each of rs.pkColumns, whether it's declared
+// DESC in the primary key and whether it's nullable, and marks which of
+// rs.plan.Table.Columns are generated. schema.Table doesn't carry any of
+// this, so it's read straight out of information_schema once per plan.
+func (rs *rowStreamer) loadPKColumnMeta() error {
+	conn, err := rs.mysqlConnect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	dbname := rs.cp.DbName
+	tableName := rs.plan.Table.Name
+	rs.pkDescending = make([]bool, len(rs.pkColumns))
+	rs.pkNullable = make([]bool, len(rs.pkColumns))
+	for i, pk := range rs.pkColumns {
+		colName := rs.plan.Table.Columns[pk].Name.String()
+
+		qr, err := conn.ExecuteFetch(fmt.Sprintf(
+			"select collation from information_schema.statistics where table_schema=%s and table_name=%s and column_name=%s and index_name='PRIMARY'",
+			sqlString(dbname), sqlString(tableName), sqlString(colName)), 1, false)
+		if err != nil {
+			return err
+		}
+		if len(qr.Rows) == 1 && !qr.Rows[0][0].IsNull() {
+			rs.pkDescending[i] = qr.Rows[0][0].ToString() == "D"
+		}
+
+		qr, err = conn.ExecuteFetch(fmt.Sprintf(
+			"select is_nullable from information_schema.columns where table_schema=%s and table_name=%s and column_name=%s",
+			sqlString(dbname), sqlString(tableName), sqlString(colName)), 1, false)
+		if err != nil {
+			return err
+		}
+		if len(qr.Rows) == 1 {
+			rs.pkNullable[i] = qr.Rows[0][0].ToString() == "YES"
+		}
+	}
+
+	rs.generatedColumns = make([]bool, len(rs.plan.Table.Columns))
+	qr, err := conn.ExecuteFetch(fmt.Sprintf(
+		"select column_name from information_schema.columns where table_schema=%s and table_name=%s and generation_expression != ''",
+		sqlString(dbname), sqlString(tableName)), len(rs.plan.Table.Columns), false)
+	if err != nil {
+		return err
+	}
+	for _, row := range qr.Rows {
+		name := row[0].ToString()
+		for i, col := range rs.plan.Table.Columns {
+			if col.Name.String() == name {
+				rs.generatedColumns[i] = true
+			}
+		}
+	}
+	return nil
+}
+
 func buildPKColumns(st *schema.Table) ([]int, error) {
 	if len(st.PKColumns) == 0 {
 		pkColumns := make([]int, len(st.Columns))
@@ -146,42 +492,269 @@ func buildPKColumns(st *schema.Table) ([]int, error) {
 }
 
 func (rs *rowStreamer) buildSelect() (string, error) {
+	return rs.buildSelectForRange(rs.lastpk, nil)
+}
+
+// buildSelectForRange is like buildSelect, but additionally bounds the scan
+// above by hi (inclusive) when hi is non-empty. This is what lets a
+// parallel copy carve the table into disjoint, independently resumable
+// chunks: each chunk's lastpk acts as its own lower-bound checkpoint, and hi
+// is the upper boundary the planner assigned it.
+func (rs *rowStreamer) buildSelectForRange(lastpk []sqltypes.Value, hi []sqltypes.Value) (string, error) {
 	buf := sqlparser.NewTrackedBuffer(nil)
 	buf.Myprintf("select ")
 	prefix := ""
-	for _, col := range rs.plan.Table.Columns {
+	for i, col := range rs.plan.Table.Columns {
+		if i < len(rs.generatedColumns) && rs.generatedColumns[i] {
+			// Generated columns can't be inserted downstream, but they
+			// still need to appear in the ORDER BY below.
+			continue
+		}
 		buf.Myprintf("%s%v", prefix, col.Name)
 		prefix = ", "
 	}
 	buf.Myprintf(" from %v", sqlparser.NewTableIdent(rs.plan.Table.Name))
-	if len(rs.lastpk) != 0 {
-		if len(rs.lastpk) != len(rs.pkColumns) {
-			return "", fmt.Errorf("primary key values don't match length: %v vs %v", rs.lastpk, rs.pkColumns)
+	wherePrefix := " where "
+	if len(lastpk) != 0 {
+		if len(lastpk) != len(rs.pkColumns) {
+			return "", fmt.Errorf("primary key values don't match length: %v vs %v", lastpk, rs.pkColumns)
 		}
-		buf.WriteString(" where ")
+		buf.WriteString(wherePrefix)
+		wherePrefix = " and "
+		rs.writeResumeCondition(buf, lastpk)
+	}
+	if len(hi) != 0 {
+		if len(hi) != len(rs.pkColumns) {
+			return "", fmt.Errorf("primary key values don't match length: %v vs %v", hi, rs.pkColumns)
+		}
+		// Range boundaries are always expressed in ascending value
+		// order (that's how planPKRanges sampled them), independent of
+		// how the PK is declared for scan order below.
+		buf.WriteString(wherePrefix)
+		buf.Myprintf("(")
 		prefix := ""
-		for lastcol := len(rs.pkColumns) - 1; lastcol >= 0; lastcol-- {
-			buf.Myprintf("%s(", prefix)
-			prefix = " or "
-			for i, pk := range rs.pkColumns[:lastcol] {
-				buf.Myprintf("%v = ", rs.plan.Table.Columns[pk].Name)
-				rs.lastpk[i].EncodeSQL(buf)
-				buf.Myprintf(" and ")
-			}
-			buf.Myprintf("%v > ", rs.plan.Table.Columns[rs.pkColumns[lastcol]].Name)
-			rs.lastpk[lastcol].EncodeSQL(buf)
-			buf.Myprintf(")")
+		for _, pk := range rs.pkColumns {
+			buf.Myprintf("%s%v", prefix, rs.plan.Table.Columns[pk].Name)
+			prefix = ", "
 		}
+		buf.Myprintf(") <= (")
+		prefix = ""
+		for _, v := range hi {
+			buf.Myprintf("%s", prefix)
+			v.EncodeSQL(buf)
+			prefix = ", "
+		}
+		buf.Myprintf(")")
 	}
-	buf.Myprintf(" order by ", sqlparser.NewTableIdent(rs.plan.Table.Name))
+	buf.Myprintf(" order by ")
 	prefix = ""
-	for _, pk := range rs.pkColumns {
-		buf.Myprintf("%s%v", prefix, rs.plan.Table.Columns[pk].Name)
+	for i, pk := range rs.pkColumns {
+		dir := ""
+		if i < len(rs.pkDescending) && rs.pkDescending[i] {
+			dir = " desc"
+		}
+		buf.Myprintf("%s%v%s", prefix, rs.plan.Table.Columns[pk].Name, dir)
 		prefix = ", "
 	}
 	return buf.String(), nil
 }
 
+// writeResumeCondition writes the predicate that selects rows strictly
+// after lastpk in scan order, honoring each PK column's ASC/DESC direction.
+// When every column shares the same direction and none of them are
+// nullable, it emits the standard-SQL row-value form
+// `(a, b, c) > (?, ?, ?)`, which MySQL 8 can evaluate directly against the
+// PK index. Otherwise it falls back to the general lexicographic tuple
+// comparison, using `<=>` for the equality legs so a NULL PK column
+// compares correctly, and accounting for MySQL's NULL sort order (first
+// under ASC, last under DESC) at the trailing, inequality leg of each
+// disjunct: a NULL there is rewritten to an `is not null` check under ASC,
+// or dropped entirely under DESC since nothing can sort after it; a
+// non-NULL value on a nullable DESC column also admits `is null`, since
+// NULL sorts after it.
+func (rs *rowStreamer) writeResumeCondition(buf *sqlparser.TrackedBuffer, lastpk []sqltypes.Value) {
+	if desc, uniform := rs.uniformPKDirection(); uniform && !rs.anyPKNullable() {
+		op := ">"
+		if desc {
+			op = "<"
+		}
+		buf.Myprintf("(")
+		prefix := ""
+		for _, pk := range rs.pkColumns {
+			buf.Myprintf("%s%v", prefix, rs.plan.Table.Columns[pk].Name)
+			prefix = ", "
+		}
+		buf.Myprintf(") %s (", op)
+		prefix = ""
+		for _, v := range lastpk {
+			buf.Myprintf("%s", prefix)
+			v.EncodeSQL(buf)
+			prefix = ", "
+		}
+		buf.Myprintf(")")
+		return
+	}
+
+	wrote := false
+	prefix := ""
+	for lastcol := len(rs.pkColumns) - 1; lastcol >= 0; lastcol-- {
+		if rs.pkDescending[lastcol] && lastpk[lastcol].IsNull() {
+			// NULL sorts last under descending order, so no row can
+			// follow it at this position: this disjunct can never match.
+			continue
+		}
+		buf.Myprintf("%s(", prefix)
+		prefix = " or "
+		wrote = true
+		for i, pk := range rs.pkColumns[:lastcol] {
+			eq := "="
+			if rs.pkNullable[i] {
+				eq = "<=>"
+			}
+			buf.Myprintf("%v %s ", rs.plan.Table.Columns[pk].Name, eq)
+			lastpk[i].EncodeSQL(buf)
+			buf.Myprintf(" and ")
+		}
+		if lastpk[lastcol].IsNull() {
+			// NULL sorts first under ascending order, so every non-NULL
+			// value sorts after it.
+			buf.Myprintf("%v is not null", rs.plan.Table.Columns[rs.pkColumns[lastcol]].Name)
+		} else if rs.pkDescending[lastcol] && rs.pkNullable[lastcol] {
+			// NULL sorts last under descending order, so NULL rows follow
+			// every non-NULL value in this column.
+			colName := rs.plan.Table.Columns[rs.pkColumns[lastcol]].Name
+			buf.Myprintf("(%v < ", colName)
+			lastpk[lastcol].EncodeSQL(buf)
+			buf.Myprintf(" or %v is null)", colName)
+		} else {
+			op := ">"
+			if rs.pkDescending[lastcol] {
+				op = "<"
+			}
+			buf.Myprintf("%v %s ", rs.plan.Table.Columns[rs.pkColumns[lastcol]].Name, op)
+			lastpk[lastcol].EncodeSQL(buf)
+		}
+		buf.Myprintf(")")
+	}
+	if !wrote {
+		// Every disjunct was dropped above: lastpk was already the last
+		// row in scan order, so no row can come after it.
+		buf.Myprintf("1 = 0")
+	}
+}
+
+// uniformPKDirection reports whether every PK column shares the same sort
+// direction, and if so, which one.
+func (rs *rowStreamer) uniformPKDirection() (descending bool, uniform bool) {
+	if len(rs.pkDescending) == 0 {
+		return false, true
+	}
+	first := rs.pkDescending[0]
+	for _, d := range rs.pkDescending {
+		if d != first {
+			return false, false
+		}
+	}
+	return first, true
+}
+
+func (rs *rowStreamer) anyPKNullable() bool {
+	for _, n := range rs.pkNullable {
+		if n {
+			return true
+		}
+	}
+	return false
+}
+
+// pkColumnList renders the table's primary-key columns as a comma-separated
+// identifier list, e.g. for use in an ORDER BY or OFFSET probe.
+func (rs *rowStreamer) pkColumnList() string {
+	names := make([]string, len(rs.pkColumns))
+	for i, pk := range rs.pkColumns {
+		names[i] = rs.plan.Table.Columns[pk].Name.String()
+	}
+	return strings.Join(names, ", ")
+}
+
+// pkRange is one contiguous, half-open slice of the primary-key space:
+// (lo, hi]. lo is exclusive because it is applied as a resume checkpoint
+// (see buildSelectForRange), so each sampled boundary row belongs to the
+// range it closes. An empty lo or hi means that end of the range is
+// unbounded.
+//
+// rangeID is 1-based: VStreamRowsResponse.RangeId is 0 on a serial
+// (unpartitioned) copy's packets, so starting the first parallel range at 1
+// keeps that zero value exclusive to "not a parallel copy" instead of also
+// meaning "range 0 of a parallel copy".
+type pkRange struct {
+	rangeID int32
+	lo      []sqltypes.Value
+	hi      []sqltypes.Value
+}
+
+// planPKRanges splits the table's primary-key space into n contiguous
+// ranges under conn's current lock: it counts the table's rows with
+// COUNT(*), then reads the PK at each of n-1 evenly spaced row offsets
+// (ORDER BY pk LIMIT 1 OFFSET k) as the range boundaries. It does not query
+// MIN/MAX of the PK; the first and last ranges are left unbounded instead.
+func (rs *rowStreamer) planPKRanges(conn *mysql.Conn, n int) ([]pkRange, error) {
+	if n <= 1 {
+		return []pkRange{{rangeID: 1}}, nil
+	}
+
+	qr, err := conn.ExecuteFetch(fmt.Sprintf("select count(*) from %v", sqlparser.NewTableIdent(rs.plan.Table.Name)), 1, false)
+	if err != nil {
+		return nil, err
+	}
+	total, err := parseInt64(qr.Rows[0][0])
+	if err != nil {
+		return nil, err
+	}
+	if total == 0 {
+		return []pkRange{{rangeID: 1}}, nil
+	}
+
+	pkCols := rs.pkColumnList()
+	var boundaries [][]sqltypes.Value
+	for _, offset := range pkRangeOffsets(total, n) {
+		qr, err := conn.ExecuteFetch(fmt.Sprintf("select %s from %v order by %s limit 1 offset %d", pkCols, sqlparser.NewTableIdent(rs.plan.Table.Name), pkCols, offset), 1, false)
+		if err != nil {
+			return nil, err
+		}
+		if len(qr.Rows) == 0 {
+			break
+		}
+		boundaries = append(boundaries, qr.Rows[0])
+	}
+
+	return pkRangesFromBoundaries(boundaries), nil
+}
+
+// pkRangeOffsets returns the n-1 row offsets that split a table of total
+// rows into n roughly equal-sized ranges.
+func pkRangeOffsets(total int64, n int) []int64 {
+	offsets := make([]int64, 0, n-1)
+	for i := int64(1); i < int64(n); i++ {
+		offsets = append(offsets, i*total/int64(n))
+	}
+	return offsets
+}
+
+// pkRangesFromBoundaries turns a sequence of sampled PK boundary values into
+// the contiguous, half-open pkRanges they delimit. Range IDs start at 1 (see
+// pkRange).
+func pkRangesFromBoundaries(boundaries [][]sqltypes.Value) []pkRange {
+	ranges := make([]pkRange, 0, len(boundaries)+1)
+	var lo []sqltypes.Value
+	for _, hi := range boundaries {
+		ranges = append(ranges, pkRange{rangeID: int32(len(ranges)) + 1, lo: lo, hi: hi})
+		lo = hi
+	}
+	ranges = append(ranges, pkRange{rangeID: int32(len(ranges)) + 1, lo: lo})
+	return ranges
+}
+
 func (rs *rowStreamer) streamQuery(conn *mysql.Conn, send func(*binlogdatapb.VStreamRowsResponse) error) error {
 	gtid, err := rs.startStreaming(conn)
 	if err != nil {
@@ -205,6 +778,7 @@ func (rs *rowStreamer) streamQuery(conn *mysql.Conn, send func(*binlogdatapb.VSt
 		Fields:   rs.plan.fields(),
 		Pkfields: pkfields,
 		Gtid:     gtid,
+		Progress: rs.buildProgress(),
 	})
 	if err != nil {
 		return fmt.Errorf("stream send error: %v", err)
@@ -212,7 +786,9 @@ func (rs *rowStreamer) streamQuery(conn *mysql.Conn, send func(*binlogdatapb.VSt
 
 	response := &binlogdatapb.VStreamRowsResponse{}
 	lastpk := make([]sqltypes.Value, len(rs.pkColumns))
-	byteCount := 0
+	chunker := newAdaptiveChunker(rs.targetChunkDuration)
+	batchStart := time.Now()
+	var rowsInPacket, bytesInPacket int64
 	for {
 		select {
 		case <-rs.ctx.Done():
@@ -236,26 +812,41 @@ func (rs *rowStreamer) streamQuery(conn *mysql.Conn, send func(*binlogdatapb.VSt
 		}
 		if ok {
 			response.Rows = append(response.Rows, sqltypes.RowToProto3(filtered))
+			rowsInPacket++
+			atomic.AddInt64(&rs.rowsCopied, 1)
 			for _, s := range filtered {
-				byteCount += s.Len()
+				bytesInPacket += int64(s.Len())
+				atomic.AddInt64(&rs.bytesCopied, int64(s.Len()))
 			}
 		}
 
-		if byteCount >= *PacketSize {
+		if chunker.shouldFlush(rowsInPacket, bytesInPacket) {
+			// Measure only the time spent fetching this batch; Throttle
+			// and send below can block on replica lag or network I/O, and
+			// neither reflects how fast MySQL is actually handing us rows.
+			fetchElapsed := time.Since(batchStart)
+			if err := rs.throttler.Throttle(rs.ctx); err != nil {
+				return err
+			}
 			response.Lastpk = sqltypes.RowToProto3(lastpk)
+			response.Progress = rs.buildProgress()
 			err = send(response)
 			if err != nil {
 				return err
 			}
+			chunker.recordBatch(fetchElapsed)
 			// empty the rows so we start over, but we keep the
 			// same capacity
 			response.Rows = response.Rows[:0]
-			byteCount = 0
+			rowsInPacket = 0
+			bytesInPacket = 0
+			batchStart = time.Now()
 		}
 	}
 
 	if len(response.Rows) > 0 {
 		response.Lastpk = sqltypes.RowToProto3(lastpk)
+		response.Progress = rs.buildProgress()
 		err = send(response)
 		if err != nil {
 			return err
@@ -265,7 +856,328 @@ func (rs *rowStreamer) streamQuery(conn *mysql.Conn, send func(*binlogdatapb.VSt
 	return nil
 }
 
+// buildProgress reports how far the current copy has gotten against the
+// estimate established at stream start. It's attached to every packet so a
+// UI watching the stream can compute an ETA without having to remember the
+// initial estimate itself.
+func (rs *rowStreamer) buildProgress() *binlogdatapb.RowStreamerProgress {
+	rowsCopied := atomic.LoadInt64(&rs.rowsCopied)
+	bytesCopied := atomic.LoadInt64(&rs.bytesCopied)
+	var percent float64
+	if rs.rowsEstimate > 0 {
+		percent = float64(rowsCopied) / float64(rs.rowsEstimate) * 100
+		if percent > 100 {
+			percent = 100
+		}
+	}
+	return &binlogdatapb.RowStreamerProgress{
+		RowsEstimate:    rs.rowsEstimate,
+		EstimateMethod:  string(rs.estimateRowsMethod),
+		RowsCopied:      rowsCopied,
+		BytesCopied:     bytesCopied,
+		PercentComplete: percent,
+	}
+}
+
+// streamParallel splits the table into rs.parallelism contiguous PK ranges
+// and copies each one on its own connection. Planning (the GTID capture and
+// the range sampling) always goes through LOCK TABLES today; combining
+// parallel copy with the consistent-snapshot path is left for later, since
+// it needs a way to pin every worker connection to the same snapshot.
+func (rs *rowStreamer) streamParallel() error {
+	planConn, err := rs.mysqlConnect()
+	if err != nil {
+		return err
+	}
+	gtid, ranges, err := rs.planParallelCopy(planConn)
+	planConn.Close()
+	if err != nil {
+		return err
+	}
+
+	var sendMu sync.Mutex
+	safeSend := func(resp *binlogdatapb.VStreamRowsResponse) error {
+		sendMu.Lock()
+		defer sendMu.Unlock()
+		return rs.send(resp)
+	}
+
+	var headerOnce sync.Once
+	var headerErr error
+	sendHeader := func(flds []*querypb.Field) {
+		headerOnce.Do(func() {
+			pkfields := make([]*querypb.Field, len(rs.pkColumns))
+			for i, pk := range rs.pkColumns {
+				pkfields[i] = &querypb.Field{Name: flds[pk].Name, Type: flds[pk].Type}
+			}
+			headerErr = safeSend(&binlogdatapb.VStreamRowsResponse{
+				Fields:   rs.plan.fields(),
+				Pkfields: pkfields,
+				Gtid:     gtid,
+				Progress: rs.buildProgress(),
+			})
+		})
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(ranges))
+	for _, rg := range ranges {
+		rg := rg
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := rs.streamRange(rg, sendHeader, safeSend); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	if headerErr != nil {
+		return headerErr
+	}
+	for err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// planParallelCopy locks the table just long enough to capture a GTID
+// position and sample the PK range boundaries, the same consistent view
+// streamQuery relies on for a single-range copy.
+//
+// This always takes a LOCK TABLES ... READ to plan ranges, regardless of
+// rs.useConsistentSnapshot: pinning every worker connection's range scan to
+// one shared snapshot needs a way to hand out that snapshot to connections
+// opened after it was taken, which the consistent-snapshot path doesn't yet
+// support.
+func (rs *rowStreamer) planParallelCopy(conn *mysql.Conn) (string, []pkRange, error) {
+	if rs.useConsistentSnapshot {
+		log.Warningf("table %s requested consistent-snapshot mode, but parallel copy always locks tables to plan ranges; the write stall consistent-snapshot mode is meant to avoid still applies", rs.plan.Table.Name)
+	}
+	log.Infof("Locking table %s to plan a %d-way parallel copy", rs.plan.Table.Name, rs.parallelism)
+	if _, err := conn.ExecuteFetch(fmt.Sprintf("lock tables %s read", sqlparser.String(sqlparser.NewTableIdent(rs.plan.Table.Name))), 0, false); err != nil {
+		return "", nil, err
+	}
+	defer func() {
+		if _, err := conn.ExecuteFetch("unlock tables", 0, false); err != nil {
+			log.Warningf("Unlock tables failed: %v", err)
+		}
+	}()
+
+	pos, err := conn.MasterPosition()
+	if err != nil {
+		return "", nil, err
+	}
+	rs.rowsEstimate, err = rs.estimateRows(conn)
+	if err != nil {
+		return "", nil, err
+	}
+	ranges, err := rs.planPKRanges(conn, rs.parallelism)
+	if err != nil {
+		return "", nil, err
+	}
+	return mysql.EncodePosition(pos), ranges, nil
+}
+
+// streamRange copies a single PK range on its own connection, starting just
+// after rg.lo (or at the beginning of the table, if nil) and stopping after
+// rg.hi (or at the end of the table, if nil). Each packet carries the
+// range's last sent PK in Lastpk, tagged with the range's RangeId; rg itself
+// is not updated. Stream does not take such per-range checkpoints back in:
+// a parallel copy plans its ranges over the whole table and does not apply
+// rs.lastpk.
+func (rs *rowStreamer) streamRange(rg pkRange, sendHeader func([]*querypb.Field), send func(*binlogdatapb.VStreamRowsResponse) error) error {
+	conn, err := rs.mysqlConnect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if _, err := conn.ExecuteFetch("set names binary", 1, false); err != nil {
+		return err
+	}
+
+	query, err := rs.buildSelectForRange(rg.lo, rg.hi)
+	if err != nil {
+		return err
+	}
+	if err := conn.ExecuteStreamFetch(query); err != nil {
+		return err
+	}
+
+	flds, err := conn.Fields()
+	if err != nil {
+		return err
+	}
+	sendHeader(flds)
+
+	response := &binlogdatapb.VStreamRowsResponse{RangeId: rg.rangeID}
+	lastpk := make([]sqltypes.Value, len(rs.pkColumns))
+	chunker := newAdaptiveChunker(rs.targetChunkDuration)
+	batchStart := time.Now()
+	var rowsInPacket, bytesInPacket int64
+	for {
+		select {
+		case <-rs.ctx.Done():
+			return fmt.Errorf("stream ended: %v", rs.ctx.Err())
+		default:
+		}
+
+		row, err := conn.FetchNext()
+		if err != nil {
+			return err
+		}
+		if row == nil {
+			break
+		}
+		for i, pk := range rs.pkColumns {
+			lastpk[i] = row[pk]
+		}
+		ok, filtered, err := rs.plan.filter(row)
+		if err != nil {
+			return err
+		}
+		if ok {
+			response.Rows = append(response.Rows, sqltypes.RowToProto3(filtered))
+			rowsInPacket++
+			atomic.AddInt64(&rs.rowsCopied, 1)
+			for _, s := range filtered {
+				bytesInPacket += int64(s.Len())
+				atomic.AddInt64(&rs.bytesCopied, int64(s.Len()))
+			}
+		}
+
+		if chunker.shouldFlush(rowsInPacket, bytesInPacket) {
+			// Measure only the time spent fetching this batch; Throttle
+			// and send below can block on replica lag or network I/O, and
+			// neither reflects how fast MySQL is actually handing us rows.
+			fetchElapsed := time.Since(batchStart)
+			if err := rs.throttler.Throttle(rs.ctx); err != nil {
+				return err
+			}
+			response.Lastpk = sqltypes.RowToProto3(lastpk)
+			response.Progress = rs.buildProgress()
+			if err := send(response); err != nil {
+				return err
+			}
+			chunker.recordBatch(fetchElapsed)
+			response = &binlogdatapb.VStreamRowsResponse{RangeId: rg.rangeID}
+			rowsInPacket = 0
+			bytesInPacket = 0
+			batchStart = time.Now()
+		}
+	}
+
+	if len(response.Rows) > 0 {
+		response.Lastpk = sqltypes.RowToProto3(lastpk)
+		response.Progress = rs.buildProgress()
+		if err := send(response); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (rs *rowStreamer) startStreaming(conn *mysql.Conn) (string, error) {
+	if rs.useConsistentSnapshot {
+		gtid, err := rs.startStreamingWithConsistentSnapshot(conn)
+		if err == errConsistentSnapshotUnsupported {
+			log.Warningf("%s is not consistent-snapshot capable, falling back to LOCK TABLES for %s", rs.cp.Host, rs.plan.Table.Name)
+		} else {
+			return gtid, err
+		}
+	}
+	return rs.startStreamingWithLockTables(conn)
+}
+
+// errConsistentSnapshotUnsupported is returned internally when the server
+// doesn't support a GTID-consistent-snapshot transaction, so the caller can
+// fall back to the LOCK TABLES path.
+var errConsistentSnapshotUnsupported = fmt.Errorf("server is not GTID-consistent-snapshot capable")
+
+// startStreamingWithConsistentSnapshot captures the streaming position from
+// inside a REPEATABLE READ consistent-snapshot transaction on the streaming
+// connection itself, avoiding the global read stall that LOCK TABLES causes
+// on hot tables. This mirrors how mydumper / gh-ost-style copiers take a
+// consistent dump without blocking writers.
+func (rs *rowStreamer) startStreamingWithConsistentSnapshot(conn *mysql.Conn) (string, error) {
+	gtidModeRes, err := conn.ExecuteFetch("show variables like 'gtid_mode'", 1, false)
+	if err != nil {
+		return "", err
+	}
+	if len(gtidModeRes.Rows) != 1 || gtidModeRes.Rows[0][1].ToString() != "ON" {
+		return "", errConsistentSnapshotUnsupported
+	}
+
+	if _, err := conn.ExecuteFetch("set session transaction isolation level repeatable read", 0, false); err != nil {
+		return "", err
+	}
+	if _, err := conn.ExecuteFetch("start transaction with consistent snapshot", 0, false); err != nil {
+		return "", err
+	}
+
+	gtid, err := readGTIDExecutedFromLogStatus(conn)
+	if err != nil {
+		return "", err
+	}
+
+	// Estimate the row count from inside the same transaction, so the
+	// number is consistent with the snapshot we just captured.
+	rs.rowsEstimate, err = rs.estimateRows(conn)
+	if err != nil {
+		return "", err
+	}
+
+	if err := conn.ExecuteStreamFetch(rs.sendQuery); err != nil {
+		return "", err
+	}
+
+	return gtid, nil
+}
+
+// logStatusLocal mirrors the subset of performance_schema.log_status's
+// `local` JSON column this package reads.
+type logStatusLocal struct {
+	GtidExecuted string `json:"gtid_executed"`
+}
+
+// readGTIDExecutedFromLogStatus returns the GTID set belonging to the
+// binary log position performance_schema.log_status reports at the instant
+// the row is read. Unlike a bare `select @@global.gtid_executed`, which
+// reads a global variable outside the transaction's MVCC snapshot and can
+// observe a commit that lands between START TRANSACTION and the read,
+// log_status is what mysqldump --single-transaction uses to get a position
+// that actually corresponds to the open snapshot.
+func readGTIDExecutedFromLogStatus(conn *mysql.Conn) (string, error) {
+	qr, err := conn.ExecuteFetch("select local from performance_schema.log_status", 1, false)
+	if err != nil {
+		return "", err
+	}
+	if len(qr.Rows) != 1 {
+		return "", fmt.Errorf("unexpected result for performance_schema.log_status: %v", qr.Rows)
+	}
+	return parseGTIDExecutedFromLogStatus(qr.Rows[0][0].ToBytes())
+}
+
+// parseGTIDExecutedFromLogStatus pulls gtid_executed out of the raw JSON
+// performance_schema.log_status.local column, split out of
+// readGTIDExecutedFromLogStatus so the parsing can be unit tested without a
+// live connection.
+func parseGTIDExecutedFromLogStatus(local []byte) (string, error) {
+	var parsed logStatusLocal
+	if err := json.Unmarshal(local, &parsed); err != nil {
+		return "", fmt.Errorf("parsing performance_schema.log_status.local: %v", err)
+	}
+	return parsed.GtidExecuted, nil
+}
+
+// startStreamingWithLockTables is the original position-capture path: it
+// takes a LOCK TABLES ... READ on a side connection just long enough to read
+// a matching GTID, then releases it once the streaming SELECT has started.
+func (rs *rowStreamer) startStreamingWithLockTables(conn *mysql.Conn) (string, error) {
 	lockConn, err := rs.mysqlConnect()
 	if err != nil {
 		return "", err
@@ -290,6 +1202,14 @@ func (rs *rowStreamer) startStreaming(conn *mysql.Conn) (string, error) {
 		return "", err
 	}
 
+	// Estimate the row count now, under the same lock/snapshot that
+	// produced pos, so the number stays consistent with the GTID we
+	// report below.
+	rs.rowsEstimate, err = rs.estimateRows(lockConn)
+	if err != nil {
+		return "", err
+	}
+
 	if err := conn.ExecuteStreamFetch(rs.sendQuery); err != nil {
 		return "", err
 	}
@@ -297,6 +1217,73 @@ func (rs *rowStreamer) startStreaming(conn *mysql.Conn) (string, error) {
 	return mysql.EncodePosition(pos), nil
 }
 
+// estimateRows derives a total row count for the table being copied, using
+// whichever strategy was selected for this stream. conn must be inside the
+// same lock/snapshot that will back the streaming SELECT.
+func (rs *rowStreamer) estimateRows(conn *mysql.Conn) (int64, error) {
+	switch rs.estimateRowsMethod {
+	case ExplainRowsEstimate:
+		return rs.estimateRowsByExplain(conn)
+	case CountRowsEstimate:
+		return rs.estimateRowsByCount(conn)
+	default:
+		return rs.estimateRowsByTableStatus(conn)
+	}
+}
+
+func (rs *rowStreamer) estimateRowsByTableStatus(conn *mysql.Conn) (int64, error) {
+	qr, err := conn.ExecuteFetch(fmt.Sprintf("show table status like %s", sqlString(rs.plan.Table.Name)), 1, true)
+	if err != nil {
+		return 0, err
+	}
+	if len(qr.Rows) != 1 {
+		return 0, fmt.Errorf("unexpected result for show table status like %s: %v", rs.plan.Table.Name, qr.Rows)
+	}
+	idx := -1
+	for i, fld := range qr.Fields {
+		if fld.Name == "Rows" {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		return 0, fmt.Errorf("show table status like %s did not return a Rows column", rs.plan.Table.Name)
+	}
+	return parseInt64(qr.Rows[0][idx])
+}
+
+func (rs *rowStreamer) estimateRowsByExplain(conn *mysql.Conn) (int64, error) {
+	qr, err := conn.ExecuteFetch(fmt.Sprintf("explain %s", rs.sendQuery), 1, true)
+	if err != nil {
+		return 0, err
+	}
+	if len(qr.Rows) != 1 {
+		return 0, fmt.Errorf("unexpected result for explain %s: %v", rs.sendQuery, qr.Rows)
+	}
+	idx := -1
+	for i, fld := range qr.Fields {
+		if fld.Name == "rows" {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		return 0, fmt.Errorf("explain %s did not return a rows column", rs.sendQuery)
+	}
+	return parseInt64(qr.Rows[0][idx])
+}
+
+func (rs *rowStreamer) estimateRowsByCount(conn *mysql.Conn) (int64, error) {
+	qr, err := conn.ExecuteFetch(fmt.Sprintf("select count(*) from %v", sqlparser.NewTableIdent(rs.plan.Table.Name)), 1, true)
+	if err != nil {
+		return 0, err
+	}
+	if len(qr.Rows) != 1 {
+		return 0, fmt.Errorf("unexpected result for count(*) on %s: %v", rs.plan.Table.Name, qr.Rows)
+	}
+	return parseInt64(qr.Rows[0][0])
+}
+
 func (rs *rowStreamer) mysqlConnect() (*mysql.Conn, error) {
 	cp, err := dbconfigs.WithCredentials(rs.cp)
 	if err != nil {
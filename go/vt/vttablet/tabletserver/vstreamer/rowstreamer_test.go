@@ -0,0 +1,423 @@
+/*
+Copyright 2019 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vstreamer
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/schema"
+)
+
+// testRowStreamer builds a rowStreamer whose plan has pkColumns 0..len(pkDescending)-1,
+// bypassing buildPlan/analyzeSelect so buildSelectForRange can be exercised directly.
+func testRowStreamer(pkDescending, pkNullable []bool) *rowStreamer {
+	names := []string{"a", "b", "c"}[:len(pkDescending)]
+	columns := make([]schema.TableColumn, len(names))
+	pkColumns := make([]int, len(names))
+	for i, name := range names {
+		columns[i] = schema.TableColumn{Name: sqlparser.NewColIdent(name)}
+		pkColumns[i] = i
+	}
+	return &rowStreamer{
+		plan: &Plan{
+			Table: &Table{
+				Name:    "t1",
+				Columns: columns,
+			},
+		},
+		pkColumns:    pkColumns,
+		pkDescending: pkDescending,
+		pkNullable:   pkNullable,
+	}
+}
+
+func TestBuildSelectResumeCondition(t *testing.T) {
+	val := func(n int64) sqltypes.Value {
+		return sqltypes.NewInt64(n)
+	}
+
+	tcases := []struct {
+		name         string
+		pkDescending []bool
+		pkNullable   []bool
+		lastpk       []sqltypes.Value
+		want         string
+	}{
+		{
+			name:         "2-col all ascending",
+			pkDescending: []bool{false, false},
+			pkNullable:   []bool{false, false},
+			lastpk:       []sqltypes.Value{val(1), val(2)},
+			want:         "select a, b from t1 where (a, b) > (1, 2) order by a, b",
+		},
+		{
+			name:         "2-col all descending",
+			pkDescending: []bool{true, true},
+			pkNullable:   []bool{false, false},
+			lastpk:       []sqltypes.Value{val(1), val(2)},
+			want:         "select a, b from t1 where (a, b) < (1, 2) order by a desc, b desc",
+		},
+		{
+			name:         "2-col mixed asc/desc",
+			pkDescending: []bool{false, true},
+			pkNullable:   []bool{false, false},
+			lastpk:       []sqltypes.Value{val(1), val(2)},
+			want:         "select a, b from t1 where (a = 1 and b < 2) or (a > 1) order by a, b desc",
+		},
+		{
+			name:         "3-col mixed asc/desc/asc",
+			pkDescending: []bool{false, true, false},
+			pkNullable:   []bool{false, false, false},
+			lastpk:       []sqltypes.Value{val(1), val(2), val(3)},
+			want: "select a, b, c from t1 where (a = 1 and b = 2 and c > 3) or " +
+				"(a = 1 and b < 2) or (a > 1) order by a, b desc, c",
+		},
+		{
+			name:         "3-col all descending",
+			pkDescending: []bool{true, true, true},
+			pkNullable:   []bool{false, false, false},
+			lastpk:       []sqltypes.Value{val(1), val(2), val(3)},
+			want:         "select a, b, c from t1 where (a, b, c) < (1, 2, 3) order by a desc, b desc, c desc",
+		},
+		{
+			name:         "2-col ascending with nullable second column",
+			pkDescending: []bool{false, false},
+			pkNullable:   []bool{false, true},
+			lastpk:       []sqltypes.Value{val(1), val(2)},
+			want:         "select a, b from t1 where (a = 1 and b > 2) or (a > 1) order by a, b",
+		},
+		{
+			name:         "2-col ascending with nullable second column, lastpk NULL",
+			pkDescending: []bool{false, false},
+			pkNullable:   []bool{false, true},
+			lastpk:       []sqltypes.Value{val(1), sqltypes.NULL},
+			// NULL sorts first under ascending order, so every non-NULL
+			// value in this column follows it.
+			want: "select a, b from t1 where (a = 1 and b is not null) or (a > 1) order by a, b",
+		},
+		{
+			name:         "2-col with nullable descending second column, lastpk NULL",
+			pkDescending: []bool{false, true},
+			pkNullable:   []bool{false, true},
+			lastpk:       []sqltypes.Value{val(1), sqltypes.NULL},
+			// NULL sorts last under descending order, so no row can follow
+			// it in this column; only the a > 1 disjunct can match.
+			want: "select a, b from t1 where (a > 1) order by a, b desc",
+		},
+		{
+			name:         "single nullable descending column, lastpk NULL",
+			pkDescending: []bool{true},
+			pkNullable:   []bool{true},
+			lastpk:       []sqltypes.Value{sqltypes.NULL},
+			// lastpk was already the last row in descending scan order, so
+			// no disjunct can be written and the predicate must match
+			// nothing.
+			want: "select a from t1 where 1 = 0 order by a desc",
+		},
+		{
+			name:         "3-col with nullable middle column",
+			pkDescending: []bool{false, false, true},
+			pkNullable:   []bool{false, true, false},
+			lastpk:       []sqltypes.Value{val(1), val(2), val(3)},
+			want: "select a, b, c from t1 where (a = 1 and b <=> 2 and c < 3) or " +
+				"(a = 1 and b > 2) or (a > 1) order by a, b, c desc",
+		},
+	}
+	for _, tc := range tcases {
+		t.Run(tc.name, func(t *testing.T) {
+			rs := testRowStreamer(tc.pkDescending, tc.pkNullable)
+			got, err := rs.buildSelectForRange(tc.lastpk, nil)
+			if err != nil {
+				t.Fatalf("buildSelectForRange: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("buildSelectForRange() =\n  %s\nwant:\n  %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBuildProgress(t *testing.T) {
+	tcases := []struct {
+		name           string
+		rowsEstimate   int64
+		estimateMethod RowsEstimateMethod
+		rowsCopied     int64
+		bytesCopied    int64
+		wantPercent    float64
+	}{
+		{
+			name:           "no estimate yet",
+			rowsEstimate:   0,
+			estimateMethod: TableStatusRowsEstimate,
+			rowsCopied:     5,
+			wantPercent:    0,
+		},
+		{
+			name:           "partway through",
+			rowsEstimate:   200,
+			estimateMethod: ExplainRowsEstimate,
+			rowsCopied:     50,
+			wantPercent:    25,
+		},
+		{
+			name:           "rowsCopied exceeds a stale estimate",
+			rowsEstimate:   10,
+			estimateMethod: CountRowsEstimate,
+			rowsCopied:     20,
+			wantPercent:    100,
+		},
+	}
+	for _, tc := range tcases {
+		t.Run(tc.name, func(t *testing.T) {
+			rs := &rowStreamer{
+				rowsEstimate:       tc.rowsEstimate,
+				estimateRowsMethod: tc.estimateMethod,
+			}
+			atomic.StoreInt64(&rs.rowsCopied, tc.rowsCopied)
+			atomic.StoreInt64(&rs.bytesCopied, tc.bytesCopied)
+
+			got := rs.buildProgress()
+			if got.RowsEstimate != tc.rowsEstimate {
+				t.Errorf("RowsEstimate = %d, want %d", got.RowsEstimate, tc.rowsEstimate)
+			}
+			if got.EstimateMethod != string(tc.estimateMethod) {
+				t.Errorf("EstimateMethod = %q, want %q", got.EstimateMethod, tc.estimateMethod)
+			}
+			if got.RowsCopied != tc.rowsCopied {
+				t.Errorf("RowsCopied = %d, want %d", got.RowsCopied, tc.rowsCopied)
+			}
+			if got.PercentComplete != tc.wantPercent {
+				t.Errorf("PercentComplete = %v, want %v", got.PercentComplete, tc.wantPercent)
+			}
+		})
+	}
+}
+
+func TestParseGTIDExecutedFromLogStatus(t *testing.T) {
+	tcases := []struct {
+		name    string
+		local   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "typical payload",
+			local: `{"gtid_executed": "3E11FA47-71CA-11E1-9E33-C80AA9429562:1-5"}`,
+			want:  "3E11FA47-71CA-11E1-9E33-C80AA9429562:1-5",
+		},
+		{
+			name:  "extra fields are ignored",
+			local: `{"gtid_executed": "uuid:1-5", "channels": []}`,
+			want:  "uuid:1-5",
+		},
+		{
+			name:    "malformed json",
+			local:   `not json`,
+			wantErr: true,
+		},
+	}
+	for _, tc := range tcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseGTIDExecutedFromLogStatus([]byte(tc.local))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("parseGTIDExecutedFromLogStatus() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if err == nil && got != tc.want {
+				t.Errorf("parseGTIDExecutedFromLogStatus() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPKRangeOffsets(t *testing.T) {
+	tcases := []struct {
+		name  string
+		total int64
+		n     int
+		want  []int64
+	}{
+		{name: "evenly divisible", total: 100, n: 4, want: []int64{25, 50, 75}},
+		{name: "not evenly divisible", total: 10, n: 3, want: []int64{3, 6}},
+		{name: "n of 1 has no boundaries", total: 100, n: 1, want: []int64{}},
+		{name: "more ranges than rows", total: 2, n: 5, want: []int64{0, 0, 1, 1}},
+	}
+	for _, tc := range tcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := pkRangeOffsets(tc.total, tc.n)
+			if len(got) != len(tc.want) {
+				t.Fatalf("pkRangeOffsets() = %v, want %v", got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Errorf("pkRangeOffsets() = %v, want %v", got, tc.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestPKRangesFromBoundaries(t *testing.T) {
+	val := func(n int64) sqltypes.Value { return sqltypes.NewInt64(n) }
+
+	t.Run("no boundaries yields one unbounded range", func(t *testing.T) {
+		ranges := pkRangesFromBoundaries(nil)
+		if len(ranges) != 1 || ranges[0].lo != nil || ranges[0].hi != nil || ranges[0].rangeID != 1 {
+			t.Errorf("pkRangesFromBoundaries(nil) = %+v, want one unbounded range with rangeID 1", ranges)
+		}
+	})
+
+	t.Run("boundaries chain lo to the previous hi, rangeIDs start at 1", func(t *testing.T) {
+		b1 := []sqltypes.Value{val(10)}
+		b2 := []sqltypes.Value{val(20)}
+		ranges := pkRangesFromBoundaries([][]sqltypes.Value{b1, b2})
+		if len(ranges) != 3 {
+			t.Fatalf("got %d ranges, want 3", len(ranges))
+		}
+		if ranges[0].rangeID != 1 || ranges[0].lo != nil || len(ranges[0].hi) != 1 || ranges[0].hi[0].ToString() != "10" {
+			t.Errorf("range 0 = %+v", ranges[0])
+		}
+		if ranges[1].rangeID != 2 || len(ranges[1].lo) != 1 || ranges[1].lo[0].ToString() != "10" || len(ranges[1].hi) != 1 || ranges[1].hi[0].ToString() != "20" {
+			t.Errorf("range 1 = %+v", ranges[1])
+		}
+		if ranges[2].rangeID != 3 || len(ranges[2].lo) != 1 || ranges[2].lo[0].ToString() != "20" || ranges[2].hi != nil {
+			t.Errorf("range 2 = %+v", ranges[2])
+		}
+	})
+}
+
+func TestAdaptiveChunkerShouldFlush(t *testing.T) {
+	oldPacketSize := *PacketSize
+	*PacketSize = 1000
+	defer func() { *PacketSize = oldPacketSize }()
+
+	c := newAdaptiveChunker(DefaultTargetChunkDuration)
+	c.rowsPerPacket = 50
+
+	tcases := []struct {
+		name          string
+		rowsInPacket  int64
+		bytesInPacket int64
+		want          bool
+	}{
+		{name: "under both thresholds", rowsInPacket: 10, bytesInPacket: 100, want: false},
+		{name: "row count reached", rowsInPacket: 50, bytesInPacket: 100, want: true},
+		{name: "byte ceiling reached despite few rows", rowsInPacket: 1, bytesInPacket: 1000, want: true},
+		{name: "byte ceiling exceeded", rowsInPacket: 1, bytesInPacket: 5000, want: true},
+	}
+	for _, tc := range tcases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := c.shouldFlush(tc.rowsInPacket, tc.bytesInPacket); got != tc.want {
+				t.Errorf("shouldFlush(%d, %d) = %v, want %v", tc.rowsInPacket, tc.bytesInPacket, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAdaptiveChunkerRecordBatch(t *testing.T) {
+	tcases := []struct {
+		name          string
+		rowsPerPacket int64
+		elapsed       time.Duration
+		want          int64
+	}{
+		{
+			name:          "on target leaves rowsPerPacket unchanged",
+			rowsPerPacket: 100,
+			elapsed:       DefaultTargetChunkDuration,
+			want:          100,
+		},
+		{
+			name:          "slower than target shrinks rowsPerPacket",
+			rowsPerPacket: 100,
+			elapsed:       2 * DefaultTargetChunkDuration,
+			want:          50,
+		},
+		{
+			name:          "faster than target grows rowsPerPacket",
+			rowsPerPacket: 100,
+			elapsed:       DefaultTargetChunkDuration / 2,
+			want:          200,
+		},
+		{
+			name:          "growth is capped at 2x per round",
+			rowsPerPacket: 100,
+			elapsed:       DefaultTargetChunkDuration / 10,
+			want:          200,
+		},
+		{
+			name:          "shrink is floored at 0.5x per round",
+			rowsPerPacket: 100,
+			elapsed:       10 * DefaultTargetChunkDuration,
+			want:          50,
+		},
+		{
+			name:          "zero elapsed is ignored",
+			rowsPerPacket: 100,
+			elapsed:       0,
+			want:          100,
+		},
+	}
+	for _, tc := range tcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newAdaptiveChunker(DefaultTargetChunkDuration)
+			c.rowsPerPacket = tc.rowsPerPacket
+			c.recordBatch(tc.elapsed)
+			if c.rowsPerPacket != tc.want {
+				t.Errorf("rowsPerPacket after recordBatch(%v) = %d, want %d", tc.elapsed, c.rowsPerPacket, tc.want)
+			}
+		})
+	}
+
+	t.Run("clamps to minRows", func(t *testing.T) {
+		c := newAdaptiveChunker(DefaultTargetChunkDuration)
+		c.rowsPerPacket = c.minRows
+		c.recordBatch(100 * DefaultTargetChunkDuration)
+		if c.rowsPerPacket != c.minRows {
+			t.Errorf("rowsPerPacket = %d, want minRows %d", c.rowsPerPacket, c.minRows)
+		}
+	})
+
+	t.Run("clamps to maxRows", func(t *testing.T) {
+		c := newAdaptiveChunker(DefaultTargetChunkDuration)
+		c.rowsPerPacket = c.maxRows
+		c.recordBatch(DefaultTargetChunkDuration / 100)
+		if c.rowsPerPacket != c.maxRows {
+			t.Errorf("rowsPerPacket = %d, want maxRows %d", c.rowsPerPacket, c.maxRows)
+		}
+	})
+}
+
+func TestBuildSelectSkipsGeneratedColumns(t *testing.T) {
+	rs := testRowStreamer([]bool{false}, []bool{false})
+	rs.plan.Table.Columns = append(rs.plan.Table.Columns, schema.TableColumn{Name: sqlparser.NewColIdent("gen")})
+	rs.generatedColumns = []bool{false, true}
+
+	got, err := rs.buildSelectForRange(nil, nil)
+	if err != nil {
+		t.Fatalf("buildSelectForRange: %v", err)
+	}
+	want := "select a from t1 order by a"
+	if got != want {
+		t.Errorf("buildSelectForRange() = %q, want %q", got, want)
+	}
+}